@@ -1,10 +1,14 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"go/format"
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"strings"
 
@@ -18,6 +22,7 @@ var (
 	pkg        = kingpin.Flag("pkg", "Package the generated files will be attributed").Required().String()
 	outDir     = kingpin.Flag("outdir", "Output directory").Required().String()
 	inputFiles = kingpin.Arg("files", "Files to generate bindings from").Required().Strings()
+	targets    = kingpin.Flag("target", "GOOS/GOARCH to generate bindings for, e.g. linux/amd64 or windows/386-m32 (repeatable; generates one build-tagged shim file, plus a types file when needed, per target; -m32 targets additionally require `go build -tags m32`)").Strings()
 )
 
 // Go reserved words that need to be prefixed when used as parameter names
@@ -41,10 +46,115 @@ type Function struct {
 	Name    string
 }
 
-// Param represents a parameter in a function declaration
+// Param represents a parameter in a function declaration. FuncPtr is set
+// instead of Type when the parameter itself is a C function pointer, e.g.
+// `void (*callback)(int, int)`.
 type Param struct {
-	Type string
-	Name string
+	Type    string
+	Name    string
+	FuncPtr *FuncPtr
+}
+
+// FuncPtr describes the signature of a C function pointer parameter or
+// callback typedef
+type FuncPtr struct {
+	Return string
+	Params []Param
+}
+
+// Field represents a member of a C struct. FuncPtr is set instead of Type
+// when the field itself is a C function pointer.
+type Field struct {
+	Type    string
+	Name    string
+	FuncPtr *FuncPtr
+}
+
+// Struct represents a C struct declaration to be converted
+type Struct struct {
+	Name    string
+	Comment string
+	Fields  []Field
+}
+
+// EnumValue represents a single member of a C enum
+type EnumValue struct {
+	Name  string
+	Value string
+}
+
+// Enum represents a C enum declaration to be converted
+type Enum struct {
+	Name    string
+	Comment string
+	Values  []EnumValue
+}
+
+// Typedef represents a simple `typedef Underlying Name;` declaration
+type Typedef struct {
+	Name       string
+	Underlying string
+	Comment    string
+}
+
+// Callback represents a typedef'd C function pointer, e.g.
+// `typedef void (*TraceLogCallback)(int logLevel, const char *text, va_list args);`
+type Callback struct {
+	Name    string
+	Comment string
+	FuncPtr
+}
+
+// Types holds every struct, enum, typedef, and callback declaration
+// extracted from a C source file
+type Types struct {
+	Structs   []Struct
+	Enums     []Enum
+	Typedefs  []Typedef
+	Callbacks []Callback
+}
+
+// Annotation opts a single function into wrapper generation, tuning how
+// its idiomatic wrapper is shaped. The zero Annotation (no entry in an
+// Annotations file) keeps the function as a plain raw var binding.
+type Annotation struct {
+	// Wrapper generates an unexported raw purego binding plus an exported
+	// wrapper that takes unsigned char* buffers as []byte, frees char*
+	// returns, and turns out-parameters into extra Go return values. String
+	// (char*) parameters are passed through unchanged to purego's own
+	// null-terminated string conversion.
+	Wrapper bool `json:"wrapper"`
+	// OutParams names, by C parameter name, pointer parameters that are
+	// out-parameters and should become extra Go return values instead of
+	// wrapper arguments. Parameters matching the naming convention (ending
+	// in Size/Length/Count, or "out" followed by an upper-case letter, as in
+	// outFiles) are detected automatically and don't need to be listed here.
+	OutParams []string `json:"outParams,omitempty"`
+}
+
+// Annotations maps a function name to the Annotation tuning its generated
+// binding, as parsed from a JSON file alongside the header by
+// LoadAnnotations
+type Annotations map[string]Annotation
+
+// LoadAnnotations reads a JSON file mapping function names to Annotation
+// overrides. A missing file isn't an error - it just means no function
+// opts into wrapper generation.
+func LoadAnnotations(path string) (Annotations, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Annotations{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read annotations file %s: %w", path, err)
+	}
+
+	annotations := Annotations{}
+	if err := json.Unmarshal(data, &annotations); err != nil {
+		return nil, fmt.Errorf("failed to parse annotations file %s: %w", path, err)
+	}
+
+	return annotations, nil
 }
 
 // GetFunctionsFromSource parses C source code and extracts function declarations
@@ -157,7 +267,11 @@ func GetFunctionsFromSource(source []byte) ([]Function, error) {
 
 			currentParam.Name = nodeText
 
-			if strings.Contains(currentParam.Name, "*") {
+			if params, name, ok := parseFuncPtrDeclarator(currentParam.Name); ok {
+				currentParam.FuncPtr = &FuncPtr{Return: currentParam.Type, Params: params}
+				currentParam.Name = name
+				currentParam.Type = ""
+			} else if strings.Contains(currentParam.Name, "*") {
 				currentParam.Type = currentParam.Type + " *"
 				currentParam.Name = strings.ReplaceAll(currentParam.Name, "*", "")
 			}
@@ -179,8 +293,736 @@ func GetFunctionsFromSource(source []byte) ([]Function, error) {
 	return functions, nil
 }
 
-// mapCType converts C types to their Go equivalents
-func mapCType(ctype string, isFunc bool) string {
+// GetTypesFromSource parses C source code and extracts struct, enum, and
+// simple typedef declarations
+func GetTypesFromSource(source []byte) (Types, error) {
+	parser := tree_sitter.NewParser()
+	defer parser.Close()
+	language := tree_sitter.NewLanguage(tree_sitter_c.Language())
+	if err := parser.SetLanguage(language); err != nil {
+		return Types{}, err
+	}
+
+	tree := parser.Parse(source, nil)
+	defer tree.Close()
+
+	structs, err := getStructsFromTree(language, tree, source)
+	if err != nil {
+		return Types{}, err
+	}
+
+	enums, err := getEnumsFromTree(language, tree, source)
+	if err != nil {
+		return Types{}, err
+	}
+
+	typedefs, hoistedStructs, hoistedEnums, err := getTypedefsFromTree(language, tree, source)
+	if err != nil {
+		return Types{}, err
+	}
+
+	callbacks, err := getCallbacksFromTree(language, tree, source)
+	if err != nil {
+		return Types{}, err
+	}
+
+	return Types{
+		Structs:   append(structs, hoistedStructs...),
+		Enums:     append(enums, hoistedEnums...),
+		Typedefs:  typedefs,
+		Callbacks: callbacks,
+	}, nil
+}
+
+// getStructsFromTree extracts named (tagged) struct declarations, e.g.
+// `struct Vector2 { float x; float y; };`
+func getStructsFromTree(language *tree_sitter.Language, tree *tree_sitter.Tree, source []byte) ([]Struct, error) {
+	query, err := tree_sitter.NewQuery(language, `
+(struct_specifier
+  name: (type_identifier) @struct.name
+  body: (field_declaration_list) @struct.body) @struct.declaration
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	qc := tree_sitter.NewQueryCursor()
+	defer qc.Close()
+
+	var structs []Struct
+	var current Struct
+	captures := qc.Captures(query, tree.RootNode(), source)
+	for match, index := captures.Next(); match != nil; match, index = captures.Next() {
+		capture := match.Captures[index]
+		switch query.CaptureNames()[capture.Index] {
+		case "struct.declaration":
+			current = Struct{}
+			if sibling := capture.Node.NextSibling(); sibling != nil && sibling.GrammarName() == "comment" {
+				current.Comment = sibling.Utf8Text(source)
+			}
+		case "struct.name":
+			current.Name = capture.Node.Utf8Text(source)
+		case "struct.body":
+			node := capture.Node
+			fields, hoisted := parseFieldDeclarationList(&node, current.Name, source)
+			current.Fields = fields
+			structs = append(structs, current)
+			structs = append(structs, hoisted...)
+		}
+	}
+
+	return structs, nil
+}
+
+// parseFieldDeclarationList extracts the fields of a struct/union body,
+// hoisting any anonymous nested struct fields into their own named
+// declaration (named `<parentName><FieldName>`)
+func parseFieldDeclarationList(body *tree_sitter.Node, parentName string, source []byte) ([]Field, []Struct) {
+	var fields []Field
+	var hoisted []Struct
+
+	for i := uint(0); i < body.NamedChildCount(); i++ {
+		decl := body.NamedChild(i)
+		if decl == nil || decl.GrammarName() != "field_declaration" {
+			continue
+		}
+
+		typeNode := decl.ChildByFieldName("type")
+		declaratorNode := decl.ChildByFieldName("declarator")
+		if typeNode == nil || declaratorNode == nil {
+			continue
+		}
+
+		fieldName := declaratorNode.Utf8Text(source)
+		fieldType := typeNode.Utf8Text(source)
+
+		if funcParams, name, ok := parseFuncPtrDeclarator(fieldName); ok {
+			fields = append(fields, Field{Name: name, FuncPtr: &FuncPtr{Return: fieldType, Params: funcParams}})
+			continue
+		}
+
+		pointerDepth := strings.Count(fieldName, "*")
+		if pointerDepth > 0 {
+			fieldName = strings.ReplaceAll(fieldName, "*", "")
+		}
+
+		if typeNode.GrammarName() == "struct_specifier" && typeNode.ChildByFieldName("name") == nil {
+			hoistedName := parentName + capitalize(fieldName)
+			if nestedBody := typeNode.ChildByFieldName("body"); nestedBody != nil {
+				nestedFields, nestedHoisted := parseFieldDeclarationList(nestedBody, hoistedName, source)
+				hoisted = append(hoisted, nestedHoisted...)
+				hoisted = append(hoisted, Struct{Name: hoistedName, Fields: nestedFields})
+			}
+			fieldType = hoistedName
+		}
+
+		if pointerDepth > 0 {
+			fieldType += " " + strings.Repeat("*", pointerDepth)
+		}
+
+		fields = append(fields, Field{Type: fieldType, Name: fieldName})
+	}
+
+	return fields, hoisted
+}
+
+// getEnumsFromTree extracts named (tagged) enum declarations, e.g.
+// `enum TraceLogLevel { LOG_ALL, LOG_TRACE };`
+func getEnumsFromTree(language *tree_sitter.Language, tree *tree_sitter.Tree, source []byte) ([]Enum, error) {
+	query, err := tree_sitter.NewQuery(language, `
+(enum_specifier
+  name: (type_identifier) @enum.name
+  body: (enumerator_list) @enum.body) @enum.declaration
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	qc := tree_sitter.NewQueryCursor()
+	defer qc.Close()
+
+	var enums []Enum
+	var current Enum
+	captures := qc.Captures(query, tree.RootNode(), source)
+	for match, index := captures.Next(); match != nil; match, index = captures.Next() {
+		capture := match.Captures[index]
+		switch query.CaptureNames()[capture.Index] {
+		case "enum.declaration":
+			current = Enum{}
+			if sibling := capture.Node.NextSibling(); sibling != nil && sibling.GrammarName() == "comment" {
+				current.Comment = sibling.Utf8Text(source)
+			}
+		case "enum.name":
+			current.Name = capture.Node.Utf8Text(source)
+		case "enum.body":
+			node := capture.Node
+			current.Values = parseEnumeratorList(&node, source)
+			enums = append(enums, current)
+		}
+	}
+
+	return enums, nil
+}
+
+// parseEnumeratorList extracts the members of an enum body
+func parseEnumeratorList(body *tree_sitter.Node, source []byte) []EnumValue {
+	var values []EnumValue
+
+	for i := uint(0); i < body.NamedChildCount(); i++ {
+		enumerator := body.NamedChild(i)
+		if enumerator == nil || enumerator.GrammarName() != "enumerator" {
+			continue
+		}
+
+		nameNode := enumerator.ChildByFieldName("name")
+		if nameNode == nil {
+			continue
+		}
+
+		value := EnumValue{Name: nameNode.Utf8Text(source)}
+		if valueNode := enumerator.ChildByFieldName("value"); valueNode != nil {
+			value.Value = valueNode.Utf8Text(source)
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// getTypedefsFromTree extracts typedef declarations. A typedef whose
+// underlying type is an anonymous struct or enum (the common
+// `typedef struct { ... } Name;` shape) is hoisted into a named Struct/Enum
+// instead of a Typedef, since there is no separate underlying type to alias.
+func getTypedefsFromTree(language *tree_sitter.Language, tree *tree_sitter.Tree, source []byte) ([]Typedef, []Struct, []Enum, error) {
+	query, err := tree_sitter.NewQuery(language, `
+(type_definition
+  type: _ @typedef.type
+  declarator: (type_identifier) @typedef.name) @typedef.declaration
+	`)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer query.Close()
+
+	qc := tree_sitter.NewQueryCursor()
+	defer qc.Close()
+
+	var typedefs []Typedef
+	var structs []Struct
+	var enums []Enum
+	var comment string
+	var typeNode *tree_sitter.Node
+	captures := qc.Captures(query, tree.RootNode(), source)
+	for match, index := captures.Next(); match != nil; match, index = captures.Next() {
+		capture := match.Captures[index]
+		switch query.CaptureNames()[capture.Index] {
+		case "typedef.declaration":
+			comment = ""
+			typeNode = nil
+			if sibling := capture.Node.NextSibling(); sibling != nil && sibling.GrammarName() == "comment" {
+				comment = sibling.Utf8Text(source)
+			}
+		case "typedef.type":
+			node := capture.Node
+			typeNode = &node
+		case "typedef.name":
+			name := capture.Node.Utf8Text(source)
+			if typeNode == nil {
+				continue
+			}
+
+			switch typeNode.GrammarName() {
+			case "struct_specifier":
+				// A tagged struct (`typedef struct Vector2 {...} Vector2;`) is
+				// already captured by getStructsFromTree under its tag name;
+				// only alias it here if the typedef gives it a different name.
+				if tagNode := typeNode.ChildByFieldName("name"); tagNode != nil {
+					if tagName := tagNode.Utf8Text(source); tagName != name {
+						typedefs = append(typedefs, Typedef{Name: name, Underlying: tagName, Comment: comment})
+					}
+					continue
+				}
+
+				fields, hoisted := []Field(nil), []Struct(nil)
+				if body := typeNode.ChildByFieldName("body"); body != nil {
+					fields, hoisted = parseFieldDeclarationList(body, name, source)
+				}
+				structs = append(structs, hoisted...)
+				structs = append(structs, Struct{Name: name, Comment: comment, Fields: fields})
+			case "enum_specifier":
+				// Same tagged-vs-anonymous handling as struct_specifier above.
+				if tagNode := typeNode.ChildByFieldName("name"); tagNode != nil {
+					if tagName := tagNode.Utf8Text(source); tagName != name {
+						typedefs = append(typedefs, Typedef{Name: name, Underlying: tagName, Comment: comment})
+					}
+					continue
+				}
+
+				var values []EnumValue
+				if body := typeNode.ChildByFieldName("body"); body != nil {
+					values = parseEnumeratorList(body, source)
+				}
+				enums = append(enums, Enum{Name: name, Comment: comment, Values: values})
+			default:
+				typedefs = append(typedefs, Typedef{
+					Name:       name,
+					Underlying: typeNode.Utf8Text(source),
+					Comment:    comment,
+				})
+			}
+		}
+	}
+
+	return typedefs, structs, enums, nil
+}
+
+// getCallbacksFromTree extracts typedef'd C function pointers, e.g.
+// `typedef void (*TraceLogCallback)(int logLevel, const char *text, va_list args);`
+func getCallbacksFromTree(language *tree_sitter.Language, tree *tree_sitter.Tree, source []byte) ([]Callback, error) {
+	query, err := tree_sitter.NewQuery(language, `
+(type_definition
+  type: _ @callback.return
+  declarator: (function_declarator
+    declarator: (parenthesized_declarator (pointer_declarator declarator: (type_identifier) @callback.name))
+    parameters: (parameter_list) @callback.params)) @callback.declaration
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	qc := tree_sitter.NewQueryCursor()
+	defer qc.Close()
+
+	var callbacks []Callback
+	var current Callback
+	captures := qc.Captures(query, tree.RootNode(), source)
+	for match, index := captures.Next(); match != nil; match, index = captures.Next() {
+		capture := match.Captures[index]
+		switch query.CaptureNames()[capture.Index] {
+		case "callback.declaration":
+			current = Callback{}
+			if sibling := capture.Node.NextSibling(); sibling != nil && sibling.GrammarName() == "comment" {
+				current.Comment = sibling.Utf8Text(source)
+			}
+		case "callback.return":
+			current.Return = capture.Node.Utf8Text(source)
+		case "callback.name":
+			current.Name = capture.Node.Utf8Text(source)
+		case "callback.params":
+			paramList := strings.TrimSuffix(strings.TrimPrefix(capture.Node.Utf8Text(source), "("), ")")
+			current.Params = parseArgListText(paramList)
+			callbacks = append(callbacks, current)
+		}
+	}
+
+	return callbacks, nil
+}
+
+// capitalize upper-cases the first rune of name so generated struct fields
+// are exported
+func capitalize(name string) string {
+	if name == "" {
+		return name
+	}
+
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// funcPtrDeclaratorPattern matches a C function-pointer declarator such as
+// "(*callback)(int, int)" or "(*onLoad)(void)", capturing the parameter name
+// and its raw (unparsed) argument list
+var funcPtrDeclaratorPattern = regexp.MustCompile(`^\(\s*\*+\s*([A-Za-z_]\w*)\s*\)\s*\((.*)\)$`)
+
+// parseFuncPtrDeclarator recognizes a C function-pointer declarator, e.g. the
+// `(*callback)(int, int)` in `void (*callback)(int, int)`, and returns its
+// parsed argument list and name. ok is false if declarator isn't shaped like
+// a function pointer.
+func parseFuncPtrDeclarator(declarator string) (params []Param, name string, ok bool) {
+	match := funcPtrDeclaratorPattern.FindStringSubmatch(declarator)
+	if match == nil {
+		return nil, "", false
+	}
+
+	return parseArgListText(match[2]), match[1], true
+}
+
+// parseArgListText parses a raw, comma-separated C parameter list (without
+// surrounding parens) into Params. Used for both inline function-pointer
+// parameters and callback typedef declarations.
+func parseArgListText(argList string) []Param {
+	argList = strings.TrimSpace(argList)
+	if argList == "" || argList == "void" {
+		return nil
+	}
+
+	var params []Param
+	for _, arg := range splitTopLevelCommas(argList) {
+		params = append(params, parseSimpleParam(arg))
+	}
+
+	return params
+}
+
+// splitTopLevelCommas splits a C parameter list on commas that aren't nested
+// inside parentheses
+func splitTopLevelCommas(s string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// parseSimpleParam splits a single "type name" parameter into its
+// components. The trailing token is assumed to be the parameter name,
+// matching the naming convention used throughout raylib-style headers.
+func parseSimpleParam(arg string) Param {
+	isPointer := strings.Contains(arg, "*")
+	arg = strings.TrimSpace(strings.ReplaceAll(arg, "*", ""))
+
+	fields := strings.Fields(arg)
+	if len(fields) == 0 {
+		return Param{}
+	}
+
+	param := Param{Type: strings.Join(fields, " ")}
+	if len(fields) > 1 {
+		param.Type = strings.Join(fields[:len(fields)-1], " ")
+		param.Name = fields[len(fields)-1]
+	}
+
+	if isPointer {
+		param.Type += " *"
+	}
+
+	return param
+}
+
+// Target describes the GOOS/GOARCH a set of bindings is generated for,
+// optionally running in 32-bit compatibility mode (-m32) on a 64-bit arch.
+// The zero Target means "no explicit target was requested": mapCType then
+// keeps its historical platform-agnostic mapping and generated files carry
+// no //go:build line, matching the tool's behavior before targets existed.
+type Target struct {
+	GOOS   string
+	GOARCH string
+	M32    bool
+}
+
+// ParseTarget parses a --target flag value shaped like "linux/amd64" or
+// "linux/386-m32"
+func ParseTarget(s string) (Target, error) {
+	s = strings.TrimSpace(s)
+	m32 := strings.HasSuffix(s, "-m32")
+	s = strings.TrimSuffix(s, "-m32")
+
+	goos, goarch, ok := strings.Cut(s, "/")
+	if !ok || goos == "" || goarch == "" {
+		return Target{}, fmt.Errorf("invalid target %q: expected GOOS/GOARCH", s)
+	}
+
+	return Target{GOOS: goos, GOARCH: goarch, M32: m32}, nil
+}
+
+// isZero reports whether t is the zero Target, meaning no explicit --target
+// was given
+func (t Target) isZero() bool {
+	return t == Target{}
+}
+
+// is64Bit reports whether values native to this target (pointers, `long` on
+// Unix) are 64 bits wide
+func (t Target) is64Bit() bool {
+	if t.M32 {
+		return false
+	}
+
+	switch t.GOARCH {
+	case "amd64", "arm64", "ppc64", "ppc64le", "mips64", "mips64le", "riscv64", "s390x":
+		return true
+	default:
+		return false
+	}
+}
+
+// CLong returns the concrete Go type `long` aliases to on this target:
+// always 32-bit on windows (LLP64), and on everything else 32- or 64-bit
+// depending on the arch's word size (LP64)
+func (t Target) CLong() string {
+	if t.GOOS == "windows" || !t.is64Bit() {
+		return "int32"
+	}
+
+	return "int64"
+}
+
+// CSizeT returns the concrete Go type `size_t` aliases to on this target:
+// an unsigned integer the width of a pointer
+func (t Target) CSizeT() string {
+	if t.is64Bit() {
+		return "uint64"
+	}
+
+	return "uint32"
+}
+
+// CWChar returns the concrete Go type `wchar_t` aliases to on this target:
+// a UTF-16 code unit on windows, 32 bits everywhere else
+func (t Target) CWChar() string {
+	if t.GOOS == "windows" {
+		return "uint16"
+	}
+
+	return "int32"
+}
+
+// FileSuffix returns the filename suffix distinguishing this target's
+// generated file from others, e.g. "linux_amd64" or "linux_386_m32"
+func (t Target) FileSuffix() string {
+	suffix := fmt.Sprintf("%s_%s", t.GOOS, t.GOARCH)
+	if t.M32 {
+		suffix += "_m32"
+	}
+
+	return suffix
+}
+
+// BuildTag returns the //go:build line gating a file to this target. Go's
+// GOOS/GOARCH vocabulary has no way to express a 32-bit compatibility mode on
+// a 64-bit arch, so an M32 target additionally requires the "m32" build tag -
+// callers must pass `-tags m32` to select it - keeping it from colliding with
+// a plain build of the same GOOS/GOARCH.
+func (t Target) BuildTag() string {
+	if t.M32 {
+		return fmt.Sprintf("//go:build %s && %s && m32\n\n", t.GOOS, t.GOARCH)
+	}
+
+	return fmt.Sprintf("//go:build %s && %s\n\n", t.GOOS, t.GOARCH)
+}
+
+// renderFuncPtrType converts a parsed C function pointer into a Go func
+// type, recursively mapping its argument and return types through mapCType
+func renderFuncPtrType(fp FuncPtr, target Target) string {
+	argTypes := make([]string, 0, len(fp.Params))
+	for _, p := range fp.Params {
+		if p.Name == "" {
+			argTypes = append(argTypes, mapCType(p.Type, false, target))
+			continue
+		}
+		argTypes = append(argTypes, fmt.Sprintf("%s %s", p.Name, mapCType(p.Type, false, target)))
+	}
+
+	ret := mapCType(fp.Return, true, target)
+	if ret == "" {
+		return fmt.Sprintf("func(%s)", strings.Join(argTypes, ", "))
+	}
+
+	return fmt.Sprintf("func(%s) %s", strings.Join(argTypes, ", "), ret)
+}
+
+// outParamNamePattern matches the conventional naming of a C out-parameter:
+// a camelCase "out"-prefixed name (outFiles, but not outline), or one ending
+// in Size/Length/Count (any case), as in the
+// common `T *LoadX(..., int *dataSize)` shape
+var outParamNamePattern = regexp.MustCompile(`^out[A-Z]|(?i:(size|length|count)$)`)
+
+// isOutParam reports whether p should become an extra Go return value in a
+// generated wrapper instead of a wrapper argument: p must be a pointer to a
+// plain (non-func-ptr) type, and either ann names it explicitly or its name
+// matches the out-parameter convention.
+func isOutParam(p Param, ann Annotation) bool {
+	if p.FuncPtr != nil || !strings.Contains(p.Type, "*") {
+		return false
+	}
+
+	if slices.Contains(ann.OutParams, p.Name) {
+		return true
+	}
+
+	return outParamNamePattern.MatchString(p.Name)
+}
+
+// isBufferParam reports whether p is an `unsigned char *`-style byte
+// buffer, which a wrapper accepts as a Go []byte instead of relying on
+// purego's built-in (null-terminated, UTF-8 only) string conversion
+func isBufferParam(p Param, target Target) bool {
+	return p.FuncPtr == nil && mapCType(p.Type, false, target) == "*uint8"
+}
+
+// stripOuterPointer removes a single level of pointer indirection from a C
+// type, e.g. "char **" -> "char *", "int *" -> "int". Used to recover the
+// type an out-parameter points to, as opposed to stripping every `*` (which
+// would collapse a `char **` out-param down to `char`).
+func stripOuterPointer(ctype string) string {
+	ctype = strings.TrimSpace(ctype)
+	idx := strings.LastIndex(ctype, "*")
+	if idx == -1 {
+		return ctype
+	}
+
+	return strings.TrimSpace(ctype[:idx] + ctype[idx+1:])
+}
+
+// wrapperFuncs holds the generated source for a wrapped function: the
+// unexported raw purego binding and the idiomatic exported wrapper around
+// it, plus which shared helpers (cStringToGo and its _free/_strlen
+// bindings) the wrapper relies on.
+type wrapperFuncs struct {
+	rawVar     string
+	wrapper    string
+	needsCFree bool
+}
+
+// buildWrapper renders the raw binding and exported wrapper for a function
+// annotated with Wrapper: true. Out-parameters (per ann or naming
+// convention) become extra return values, unsigned char* buffer parameters
+// become []byte, and a char* return is copied into a Go string and freed
+// via the C library's free, instead of leaking the C allocation the way a
+// plain purego string conversion would.
+func buildWrapper(f Function, ann Annotation, target Target) wrapperFuncs {
+	var rawArgs, wrapperParams, callArgs, outNames, outTypes []string
+
+	for _, p := range f.Params {
+		name := p.Name
+		if slices.Contains(reservedWords, name) {
+			name = "_" + name
+		}
+
+		switch {
+		case p.FuncPtr != nil:
+			paramType := renderFuncPtrType(*p.FuncPtr, target)
+			rawArgs = append(rawArgs, fmt.Sprintf("%s %s", name, paramType))
+			wrapperParams = append(wrapperParams, fmt.Sprintf("%s %s", name, paramType))
+			callArgs = append(callArgs, name)
+		case isOutParam(p, ann):
+			pointeeType := mapCType(stripOuterPointer(p.Type), false, target)
+			outNames = append(outNames, name)
+			outTypes = append(outTypes, pointeeType)
+			rawArgs = append(rawArgs, fmt.Sprintf("%s *%s", name, pointeeType))
+			callArgs = append(callArgs, "&"+name)
+		case isBufferParam(p, target):
+			rawArgs = append(rawArgs, fmt.Sprintf("%s *uint8", name))
+			wrapperParams = append(wrapperParams, fmt.Sprintf("%s []byte", name))
+			callArgs = append(callArgs, fmt.Sprintf("(*uint8)(unsafe.Pointer(unsafe.SliceData(%s)))", name))
+		default:
+			paramType := mapCType(p.Type, false, target)
+			rawArgs = append(rawArgs, fmt.Sprintf("%s %s", name, paramType))
+			wrapperParams = append(wrapperParams, fmt.Sprintf("%s %s", name, paramType))
+			callArgs = append(callArgs, name)
+		}
+	}
+
+	retType := mapCType(f.Type, true, target)
+	returnsCString := retType == "string"
+	rawRetType := retType
+	if returnsCString {
+		rawRetType = "unsafe.Pointer"
+	}
+
+	var raw strings.Builder
+	raw.WriteString(fmt.Sprintf("var _%s func(%s)", f.Name, strings.Join(rawArgs, ", ")))
+	if rawRetType != "" {
+		raw.WriteString(" " + rawRetType)
+	}
+
+	wrapperReturns := []string{}
+	switch {
+	case returnsCString:
+		wrapperReturns = append(wrapperReturns, "string")
+	case retType != "":
+		wrapperReturns = append(wrapperReturns, retType)
+	}
+	wrapperReturns = append(wrapperReturns, outTypes...)
+
+	var wrapper strings.Builder
+	if f.Comment != "" {
+		wrapper.WriteString(f.Comment + "\n")
+	}
+	wrapper.WriteString(fmt.Sprintf("func %s(%s)", f.Name, strings.Join(wrapperParams, ", ")))
+	switch len(wrapperReturns) {
+	case 0:
+	case 1:
+		wrapper.WriteString(" " + wrapperReturns[0])
+	default:
+		wrapper.WriteString(" (" + strings.Join(wrapperReturns, ", ") + ")")
+	}
+	wrapper.WriteString(" {\n")
+
+	for i, name := range outNames {
+		wrapper.WriteString(fmt.Sprintf("\tvar %s %s\n", name, outTypes[i]))
+	}
+
+	call := fmt.Sprintf("_%s(%s)", f.Name, strings.Join(callArgs, ", "))
+	switch {
+	case returnsCString:
+		wrapper.WriteString(fmt.Sprintf("\tret := %s\n", call))
+		wrapper.WriteString(fmt.Sprintf("\treturn %s\n", strings.Join(append([]string{"cStringToGo(ret)"}, outNames...), ", ")))
+	case retType != "":
+		wrapper.WriteString(fmt.Sprintf("\tret := %s\n", call))
+		wrapper.WriteString(fmt.Sprintf("\treturn %s\n", strings.Join(append([]string{"ret"}, outNames...), ", ")))
+	case len(outNames) > 0:
+		wrapper.WriteString("\t" + call + "\n")
+		wrapper.WriteString(fmt.Sprintf("\treturn %s\n", strings.Join(outNames, ", ")))
+	default:
+		wrapper.WriteString("\t" + call + "\n")
+	}
+	wrapper.WriteString("}\n\n")
+
+	return wrapperFuncs{rawVar: raw.String(), wrapper: wrapper.String(), needsCFree: returnsCString}
+}
+
+// cStringToGoHelper is the shared helper emitted into a generated shim file
+// when at least one wrapped function returns a char*: it copies the C
+// string into a Go string, then frees the original C allocation so the
+// caller never has to.
+const cStringToGoHelper = `// cStringToGo copies a NUL-terminated C string into a Go string and frees
+// the original C allocation.
+func cStringToGo(ptr unsafe.Pointer) string {
+	if ptr == nil {
+		return ""
+	}
+	length := _strlen(ptr)
+	result := string(unsafe.Slice((*byte)(ptr), int(length)))
+	_free(ptr)
+	return result
+}
+
+`
+
+// mapCType converts C types to their Go equivalents. target controls the
+// mapping of widths that vary across platforms (`long`, `size_t`,
+// `wchar_t`): with the zero Target these keep their historical fixed
+// mapping; otherwise mapCType returns the name of a per-target alias
+// (CLong, CSizeT, CWChar) that the generated file defines via Target.CLong,
+// Target.CSizeT, and Target.CWChar, so the public API stays the same
+// identifier across every target build.
+func mapCType(ctype string, isFunc bool, target Target) string {
+	if strings.Count(ctype, "*") > 1 {
+		// Peel one pointer level at a time (mirroring stripOuterPointer's use
+		// for out-params) so e.g. "char **" maps to "*string", not "string" -
+		// blanket-stripping every `*` up front would collapse all levels of
+		// indirection into one.
+		return "*" + mapCType(stripOuterPointer(ctype), isFunc, target)
+	}
+
 	isPointerType := strings.Contains(ctype, "*")
 	goType := strings.ReplaceAll(ctype, "RLAPI", "")
 	goType = strings.ReplaceAll(goType, "*", "")
@@ -199,9 +1041,22 @@ func mapCType(ctype string, isFunc bool) string {
 	// Map C types to appropriate Go types
 	mappedType := goType
 	switch goType {
-	// TODO(Kamefrede): Check sizeof long and int
-	case "int", "long":
+	case "int":
 		mappedType = "int32"
+	case "long":
+		if target.isZero() {
+			mappedType = "int32"
+		} else {
+			mappedType = "CLong"
+		}
+	case "size_t":
+		if !target.isZero() {
+			mappedType = "CSizeT"
+		}
+	case "wchar_t":
+		if !target.isZero() {
+			mappedType = "CWChar"
+		}
 	case "unsigned int":
 		mappedType = "uint32"
 	case "float":
@@ -228,10 +1083,49 @@ func mapCType(ctype string, isFunc bool) string {
 	return "*" + mappedType
 }
 
-// GeneratePureGoShim creates Go bindings for C functions using purego
-func GeneratePureGoShim(functions []Function, outdir, inputFilePath, packageName string) error {
+// GeneratePureGoShim creates Go bindings for C functions using purego and
+// writes them to outdir/<name>.go. If target is non-zero, the file is
+// suffixed with the target (outdir/<name>_<goos>_<goarch>.go) so that
+// generating for several targets produces one file per target.
+func GeneratePureGoShim(functions []Function, outdir, inputFilePath, packageName string, target Target, annotations Annotations) error {
+	baseName := filepath.Base(inputFilePath)
+	fileName := baseName[:len(baseName)-len(filepath.Ext(baseName))]
+	if !target.isZero() {
+		fileName += "_" + target.FileSuffix()
+	}
+
+	if err := os.MkdirAll(outdir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	outPath := filepath.Join(outdir, fileName+".go")
+	file, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return WritePureGoShim(file, functions, inputFilePath, packageName, target, annotations)
+}
+
+// WritePureGoShim renders Go bindings for C functions using purego and writes
+// the gofmt'd result to w, so callers (tests, or another Go program driving
+// the generator) can direct the output somewhere other than a file on disk.
+// If target is non-zero, the output is gated behind a //go:build line for
+// that target and declares CLong/CSizeT/CWChar aliases sized for it, so the
+// same symbols stay the exported contract across differently-targeted files.
+// Functions named in annotations with Wrapper: true get an idiomatic
+// exported wrapper around an unexported raw binding instead of a plain var;
+// every other function keeps the plain var binding.
+func WritePureGoShim(w io.Writer, functions []Function, inputFilePath, packageName string, target Target, annotations Annotations) error {
 	var builder strings.Builder
 
+	if !target.isZero() {
+		builder.WriteString(target.BuildTag())
+	}
+
 	// Write package declaration and imports
 	builder.WriteString(fmt.Sprintf("package %s\n\n", packageName))
 	builder.WriteString("import (\n")
@@ -239,16 +1133,39 @@ func GeneratePureGoShim(functions []Function, outdir, inputFilePath, packageName
 	builder.WriteString("\t\"github.com/ebitengine/purego\"\n")
 	builder.WriteString(")\n\n")
 
+	if !target.isZero() {
+		builder.WriteString(fmt.Sprintf("type CLong = %s\n", target.CLong()))
+		builder.WriteString(fmt.Sprintf("type CSizeT = %s\n", target.CSizeT()))
+		builder.WriteString(fmt.Sprintf("type CWChar = %s\n\n", target.CWChar()))
+	}
+
+	var wrapped []wrapperFuncs
+	needsCFree := false
+
 	// Generate function declarations
 	for _, f := range functions {
+		if ann := annotations[f.Name]; ann.Wrapper {
+			wf := buildWrapper(f, ann, target)
+			needsCFree = needsCFree || wf.needsCFree
+			wrapped = append(wrapped, wf)
+			builder.WriteString(wf.rawVar + "\n")
+			continue
+		}
+
 		argTypes := []string{}
 		for _, p := range f.Params {
 			if slices.Contains(reservedWords, p.Name) {
 				p.Name = "_" + p.Name
 			}
-			argTypes = append(argTypes, fmt.Sprintf("%s %s", p.Name, mapCType(p.Type, false)))
+
+			paramType := mapCType(p.Type, false, target)
+			if p.FuncPtr != nil {
+				paramType = renderFuncPtrType(*p.FuncPtr, target)
+			}
+
+			argTypes = append(argTypes, fmt.Sprintf("%s %s", p.Name, paramType))
 		}
-		ret := mapCType(f.Type, true)
+		ret := mapCType(f.Type, true, target)
 		builder.WriteString(fmt.Sprintf("var %s func(%s)", f.Name, strings.Join(argTypes, ", ")))
 		if ret != "" {
 			builder.WriteString(fmt.Sprintf(" %s\n", ret))
@@ -257,24 +1174,175 @@ func GeneratePureGoShim(functions []Function, outdir, inputFilePath, packageName
 		}
 	}
 
+	if needsCFree {
+		builder.WriteString("var _free func(unsafe.Pointer)\n")
+		builder.WriteString("var _strlen func(unsafe.Pointer) uintptr\n\n")
+		builder.WriteString(cStringToGoHelper)
+	}
+
+	for _, wf := range wrapped {
+		builder.WriteString(wf.wrapper)
+	}
+
 	// Generate initialization function
 	baseName := filepath.Base(inputFilePath)
 	fileName := baseName[:len(baseName)-len(filepath.Ext(baseName))]
 
 	builder.WriteString(fmt.Sprintf("\nfunc Init%s(handle uintptr) {\n", fileName))
 	for _, f := range functions {
-		builder.WriteString(fmt.Sprintf("\tpurego.RegisterLibFunc(&%s, handle, \"%s\")\n", f.Name, f.Name))
+		varName := f.Name
+		if annotations[f.Name].Wrapper {
+			varName = "_" + f.Name
+		}
+		builder.WriteString(fmt.Sprintf("\tpurego.RegisterLibFunc(&%s, handle, \"%s\")\n", varName, f.Name))
+	}
+	if needsCFree {
+		builder.WriteString("\tpurego.RegisterLibFunc(&_free, handle, \"free\")\n")
+		builder.WriteString("\tpurego.RegisterLibFunc(&_strlen, handle, \"strlen\")\n")
 	}
 	builder.WriteString("}\n")
 
-	// Write output file
-	outPath := filepath.Join(outdir, fileName+".go")
+	source := builder.String()
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return fmt.Errorf("failed to format generated source for %s: %w\n--- source ---\n%s", fileName, err, source)
+	}
+
+	if _, err := w.Write(formatted); err != nil {
+		return fmt.Errorf("failed to write formatted source: %w", err)
+	}
+
+	return nil
+}
+
+// GenerateTypes renders Go equivalents of parsed structs, enums, and
+// typedefs, and writes them to outdir/<name>_types.go. If target is
+// non-zero, the file is suffixed with the target to match the per-target
+// shim file GeneratePureGoShim produces for the same target.
+func GenerateTypes(types Types, outdir, inputFilePath, packageName string, target Target) error {
+	baseName := filepath.Base(inputFilePath)
+	fileName := baseName[:len(baseName)-len(filepath.Ext(baseName))]
+	if !target.isZero() {
+		fileName += "_" + target.FileSuffix()
+	}
+
 	if err := os.MkdirAll(outdir, 0o755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	if err := os.WriteFile(outPath, []byte(builder.String()), 0o644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	outPath := filepath.Join(outdir, fileName+"_types.go")
+	file, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	return WriteTypes(file, types, packageName, target)
+}
+
+// WriteTypes renders Go equivalents of parsed structs, enums, and typedefs
+// and writes the gofmt'd result to w. If target is non-zero, the file is
+// gated behind the same //go:build line as the shim file generated for that
+// target, since it references the CLong/CSizeT/CWChar aliases that file
+// declares.
+func WriteTypes(w io.Writer, types Types, packageName string, target Target) error {
+	var body strings.Builder
+
+	for _, s := range types.Structs {
+		if s.Comment != "" {
+			body.WriteString(s.Comment + "\n")
+		}
+		body.WriteString(fmt.Sprintf("type %s struct {\n", s.Name))
+		for _, f := range s.Fields {
+			name := f.Name
+			if slices.Contains(reservedWords, name) {
+				name = "_" + name
+			}
+
+			fieldType := mapCType(f.Type, false, target)
+			if f.FuncPtr != nil {
+				fieldType = renderFuncPtrType(*f.FuncPtr, target)
+			}
+
+			body.WriteString(fmt.Sprintf("\t%s %s\n", capitalize(name), fieldType))
+		}
+		body.WriteString("}\n\n")
+	}
+
+	for _, e := range types.Enums {
+		if e.Comment != "" {
+			body.WriteString(e.Comment + "\n")
+		}
+		body.WriteString(fmt.Sprintf("type %s int32\n\n", e.Name))
+		if len(e.Values) == 0 {
+			continue
+		}
+
+		body.WriteString("const (\n")
+		var prevName string
+		for i, v := range e.Values {
+			switch {
+			case v.Value != "":
+				body.WriteString(fmt.Sprintf("\t%s %s = %s\n", v.Name, e.Name, v.Value))
+			case i == 0:
+				// C defaults an enumerator with no prior value to 0
+				body.WriteString(fmt.Sprintf("\t%s %s = 0\n", v.Name, e.Name))
+			default:
+				// C defines an implicit enumerator as the previous one plus
+				// one, not its position in the const block - referencing the
+				// previous constant keeps that true even after an explicit
+				// value, e.g. `FLAG_A = 5, FLAG_B` -> FLAG_B = FLAG_A + 1.
+				body.WriteString(fmt.Sprintf("\t%s %s = %s + 1\n", v.Name, e.Name, prevName))
+			}
+			prevName = v.Name
+		}
+		body.WriteString(")\n\n")
+	}
+
+	for _, t := range types.Typedefs {
+		if t.Comment != "" {
+			body.WriteString(t.Comment + "\n")
+		}
+		body.WriteString(fmt.Sprintf("type %s %s\n\n", t.Name, mapCType(t.Underlying, false, target)))
+	}
+
+	for _, c := range types.Callbacks {
+		if c.Comment != "" {
+			body.WriteString(c.Comment + "\n")
+		}
+		body.WriteString(fmt.Sprintf("type %s %s\n\n", c.Name, renderFuncPtrType(c.FuncPtr, target)))
+		body.WriteString(fmt.Sprintf(
+			"// New%s wraps fn so it can be passed to C APIs expecting a %s\nfunc New%s(fn %s) uintptr {\n\treturn purego.NewCallback(fn)\n}\n\n",
+			c.Name, c.Name, c.Name, c.Name,
+		))
+	}
+
+	var header strings.Builder
+	if !target.isZero() {
+		header.WriteString(target.BuildTag())
+	}
+	header.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+	imports := []string{}
+	if strings.Contains(body.String(), "unsafe.") {
+		imports = append(imports, "\"unsafe\"")
+	}
+	if len(types.Callbacks) > 0 {
+		imports = append(imports, "\"github.com/ebitengine/purego\"")
+	}
+	if len(imports) > 0 {
+		header.WriteString(fmt.Sprintf("import (\n\t%s\n)\n\n", strings.Join(imports, "\n\t")))
+	}
+
+	source := header.String() + body.String()
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return fmt.Errorf("failed to format generated types: %w\n--- source ---\n%s", err, source)
+	}
+
+	if _, err := w.Write(formatted); err != nil {
+		return fmt.Errorf("failed to write formatted types: %w", err)
 	}
 
 	return nil
@@ -283,6 +1351,22 @@ func GeneratePureGoShim(functions []Function, outdir, inputFilePath, packageName
 func main() {
 	kingpin.Parse()
 
+	// With no explicit --target, generate a single untagged shim file (plus
+	// an untagged _types.go file when the input declares types) per input,
+	// matching this tool's behavior before targets existed.
+	parsedTargets := []Target{{}}
+	if len(*targets) > 0 {
+		parsedTargets = make([]Target, 0, len(*targets))
+		for _, t := range *targets {
+			target, err := ParseTarget(t)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to parse --target %q: %v\n", t, err)
+				os.Exit(1)
+			}
+			parsedTargets = append(parsedTargets, target)
+		}
+	}
+
 	for _, filename := range *inputFiles {
 		func() {
 			file, err := os.Open(filename)
@@ -301,17 +1385,39 @@ func main() {
 				os.Exit(1)
 			}
 
+			types, err := GetTypesFromSource(fileBytes)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to get types from file source %s: %v\n", filename, err)
+				os.Exit(1)
+			}
+
 			functions, err := GetFunctionsFromSource(fileBytes)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Failed to get functions from file source %s: %v\n", filename, err)
 				os.Exit(1)
 			}
 
-			err = GeneratePureGoShim(functions, *outDir, filename, *pkg)
+			ext := filepath.Ext(filename)
+			annotationsPath := strings.TrimSuffix(filename, ext) + ".annotations.json"
+			annotations, err := LoadAnnotations(annotationsPath)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to generate purego shims for file %s: %v\n", filename, err)
+				fmt.Fprintf(os.Stderr, "Failed to load annotations for file %s: %v\n", filename, err)
 				os.Exit(1)
 			}
+
+			for _, target := range parsedTargets {
+				if len(types.Structs) > 0 || len(types.Enums) > 0 || len(types.Typedefs) > 0 || len(types.Callbacks) > 0 {
+					if err := GenerateTypes(types, *outDir, filename, *pkg, target); err != nil {
+						fmt.Fprintf(os.Stderr, "Failed to generate types for file %s: %v\n", filename, err)
+						os.Exit(1)
+					}
+				}
+
+				if err := GeneratePureGoShim(functions, *outDir, filename, *pkg, target, annotations); err != nil {
+					fmt.Fprintf(os.Stderr, "Failed to generate purego shims for file %s: %v\n", filename, err)
+					os.Exit(1)
+				}
+			}
 		}()
 	}
 }
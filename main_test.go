@@ -7,6 +7,41 @@ import (
 	"testing"
 )
 
+// TestWritePureGoShim verifies that generated code can be written to an
+// arbitrary io.Writer and comes back gofmt'd
+func TestWritePureGoShim(t *testing.T) {
+	functions := []Function{
+		{
+			Name: "GetRandomValue",
+			Type: "int",
+			Params: []Param{
+				{Type: "int", Name: "min"},
+				{Type: "int", Name: "max"},
+			},
+			Comment: "// Get a random value between min and max (both included)",
+		},
+	}
+
+	var buf strings.Builder
+	if err := WritePureGoShim(&buf, functions, "test.h", "raylib", Target{}, nil); err != nil {
+		t.Fatalf("Failed to write shim: %v", err)
+	}
+
+	code := buf.String()
+	expectedPatterns := []string{
+		"package raylib",
+		"var GetRandomValue func(min int32, max int32) int32",
+		"func Inittest(handle uintptr) {",
+		"purego.RegisterLibFunc(&GetRandomValue, handle, \"GetRandomValue\")",
+	}
+
+	for _, pattern := range expectedPatterns {
+		if !strings.Contains(code, pattern) {
+			t.Errorf("Generated code doesn't contain expected pattern: %s", pattern)
+		}
+	}
+}
+
 // TestGetFunctionsFromSource verifies that C function declarations are correctly parsed
 func TestGetFunctionsFromSource(t *testing.T) {
 	// Test input with various function signatures
@@ -87,16 +122,118 @@ func TestMapCType(t *testing.T) {
 		{"Vector2 *", false, "*Vector2"},
 		{"RLAPI int", false, "int32"},
 		{"const float", false, "float32"},
+		{"long", false, "int32"},
+		{"size_t", false, "size_t"},
+		{"wchar_t", false, "wchar_t"},
 	}
 
 	for _, tc := range testCases {
-		result := mapCType(tc.cType, tc.isFunc)
+		result := mapCType(tc.cType, tc.isFunc, Target{})
 		if result != tc.expected {
 			t.Errorf("mapCType(%q, %v) = %q, expected %q", tc.cType, tc.isFunc, result, tc.expected)
 		}
 	}
 }
 
+// TestMapCTypeTarget verifies that `long`, `size_t`, and `wchar_t` are
+// mapped to per-target aliases once an explicit Target is given
+func TestMapCTypeTarget(t *testing.T) {
+	testCases := []struct {
+		target   Target
+		cType    string
+		expected string
+	}{
+		{Target{GOOS: "linux", GOARCH: "amd64"}, "long", "CLong"},
+		{Target{GOOS: "linux", GOARCH: "amd64"}, "size_t", "CSizeT"},
+		{Target{GOOS: "linux", GOARCH: "amd64"}, "wchar_t", "CWChar"},
+		{Target{GOOS: "linux", GOARCH: "amd64"}, "long *", "*CLong"},
+	}
+
+	for _, tc := range testCases {
+		result := mapCType(tc.cType, false, tc.target)
+		if result != tc.expected {
+			t.Errorf("mapCType(%q, false, %+v) = %q, expected %q", tc.cType, tc.target, result, tc.expected)
+		}
+	}
+}
+
+// TestTargetWidths verifies the concrete widths Target resolves `long`,
+// `size_t`, and `wchar_t` to across platforms
+func TestTargetWidths(t *testing.T) {
+	testCases := []struct {
+		target     Target
+		long       string
+		sizeT      string
+		wchar      string
+		fileSuffix string
+	}{
+		{Target{GOOS: "linux", GOARCH: "amd64"}, "int64", "uint64", "int32", "linux_amd64"},
+		{Target{GOOS: "linux", GOARCH: "386"}, "int32", "uint32", "int32", "linux_386"},
+		{Target{GOOS: "linux", GOARCH: "amd64", M32: true}, "int32", "uint32", "int32", "linux_amd64_m32"},
+		{Target{GOOS: "darwin", GOARCH: "arm64"}, "int64", "uint64", "int32", "darwin_arm64"},
+		{Target{GOOS: "windows", GOARCH: "amd64"}, "int32", "uint64", "uint16", "windows_amd64"},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.target.CLong(); got != tc.long {
+			t.Errorf("%+v.CLong() = %q, expected %q", tc.target, got, tc.long)
+		}
+		if got := tc.target.CSizeT(); got != tc.sizeT {
+			t.Errorf("%+v.CSizeT() = %q, expected %q", tc.target, got, tc.sizeT)
+		}
+		if got := tc.target.CWChar(); got != tc.wchar {
+			t.Errorf("%+v.CWChar() = %q, expected %q", tc.target, got, tc.wchar)
+		}
+		if got := tc.target.FileSuffix(); got != tc.fileSuffix {
+			t.Errorf("%+v.FileSuffix() = %q, expected %q", tc.target, got, tc.fileSuffix)
+		}
+	}
+}
+
+// TestBuildTagM32 verifies that an M32 target's build tag requires the extra
+// "m32" tag, so it can't be selected by a plain `go build` of the same
+// GOOS/GOARCH and doesn't collide with that target's non-M32 file
+func TestBuildTagM32(t *testing.T) {
+	plain := Target{GOOS: "linux", GOARCH: "amd64"}
+	m32 := Target{GOOS: "linux", GOARCH: "amd64", M32: true}
+
+	plainTag := plain.BuildTag()
+	m32Tag := m32.BuildTag()
+
+	if plainTag == m32Tag {
+		t.Errorf("expected distinct build tags for %+v and %+v, both got %q", plain, m32, plainTag)
+	}
+	if !strings.Contains(m32Tag, "m32") {
+		t.Errorf("%+v.BuildTag() = %q, expected it to require the m32 tag", m32, m32Tag)
+	}
+	if strings.Contains(plainTag, "m32") {
+		t.Errorf("%+v.BuildTag() = %q, did not expect it to require the m32 tag", plain, plainTag)
+	}
+}
+
+// TestParseTarget verifies --target flag values are parsed into a Target
+func TestParseTarget(t *testing.T) {
+	target, err := ParseTarget("linux/amd64")
+	if err != nil {
+		t.Fatalf("Failed to parse target: %v", err)
+	}
+	if target.GOOS != "linux" || target.GOARCH != "amd64" || target.M32 {
+		t.Errorf("Expected {linux amd64 false}, got %+v", target)
+	}
+
+	target, err = ParseTarget("windows/386-m32")
+	if err != nil {
+		t.Fatalf("Failed to parse target: %v", err)
+	}
+	if target.GOOS != "windows" || target.GOARCH != "386" || !target.M32 {
+		t.Errorf("Expected {windows 386 true}, got %+v", target)
+	}
+
+	if _, err := ParseTarget("linux"); err == nil {
+		t.Error("Expected an error for a target missing GOARCH")
+	}
+}
+
 // TestGeneratePureGoShim verifies Go code generation
 func TestGeneratePureGoShim(t *testing.T) {
 	// Create a temp directory for test output
@@ -144,7 +281,7 @@ func TestGeneratePureGoShim(t *testing.T) {
 	}
 
 	// Generate code for test.h
-	err = GeneratePureGoShim(functions, tempDir, "test.h", "raylib")
+	err = GeneratePureGoShim(functions, tempDir, "test.h", "raylib", Target{}, nil)
 	if err != nil {
 		t.Fatalf("Failed to generate code: %v", err)
 	}
@@ -207,7 +344,7 @@ func TestReservedWords(t *testing.T) {
 		os.RemoveAll(tempDir)
 	}()
 
-	err = GeneratePureGoShim(functions, tempDir, "reserved.h", "test")
+	err = GeneratePureGoShim(functions, tempDir, "reserved.h", "test", Target{}, nil)
 	if err != nil {
 		t.Fatalf("Failed to generate code: %v", err)
 	}
@@ -231,3 +368,575 @@ func TestReservedWords(t *testing.T) {
 		t.Error("Normal parameter name was unnecessarily modified")
 	}
 }
+
+// TestGetTypesFromSource verifies that struct, enum, and typedef
+// declarations are correctly parsed, including anonymous nested structs
+func TestGetTypesFromSource(t *testing.T) {
+	source := []byte(`
+typedef struct Vector2 {
+    float x;
+    float y;
+} Vector2;
+
+typedef struct Camera {
+    Vector2 position;
+    struct {
+        float fovy;
+    } lens;
+} Camera;
+
+typedef enum {
+    LOG_ALL = 0,
+    LOG_TRACE,
+    LOG_DEBUG
+} TraceLogLevel;
+
+typedef unsigned int RLuint;
+	`)
+
+	types, err := GetTypesFromSource(source)
+	if err != nil {
+		t.Fatalf("Failed to parse source: %v", err)
+	}
+
+	var vector2, camera, cameraLens *Struct
+	for i := range types.Structs {
+		switch types.Structs[i].Name {
+		case "Vector2":
+			vector2 = &types.Structs[i]
+		case "Camera":
+			camera = &types.Structs[i]
+		case "CameraLens":
+			cameraLens = &types.Structs[i]
+		}
+	}
+
+	if vector2 == nil || len(vector2.Fields) != 2 {
+		t.Fatalf("Expected Vector2 struct with 2 fields, got %+v", vector2)
+	}
+
+	if camera == nil || len(camera.Fields) != 2 {
+		t.Fatalf("Expected Camera struct with 2 fields, got %+v", camera)
+	}
+	if camera.Fields[1].Type != "CameraLens" {
+		t.Errorf("Expected anonymous nested struct field hoisted to type 'CameraLens', got %q", camera.Fields[1].Type)
+	}
+
+	if cameraLens == nil || len(cameraLens.Fields) != 1 {
+		t.Fatalf("Expected hoisted CameraLens struct with 1 field, got %+v", cameraLens)
+	}
+
+	if len(types.Enums) != 1 || types.Enums[0].Name != "TraceLogLevel" || len(types.Enums[0].Values) != 3 {
+		t.Fatalf("Expected TraceLogLevel enum with 3 values, got %+v", types.Enums)
+	}
+
+	if len(types.Typedefs) != 1 || types.Typedefs[0].Name != "RLuint" || types.Typedefs[0].Underlying != "unsigned int" {
+		t.Fatalf("Expected RLuint typedef aliasing 'unsigned int', got %+v", types.Typedefs)
+	}
+}
+
+// TestGetTypesFromSourceDoublePointerField verifies that a struct field with
+// two levels of pointer indirection keeps both levels through parsing and
+// rendering, e.g. `char **names` -> `Names *string`, not a plain `string`
+func TestGetTypesFromSourceDoublePointerField(t *testing.T) {
+	source := []byte(`
+typedef struct Foo {
+    char **names;
+} Foo;
+	`)
+
+	types, err := GetTypesFromSource(source)
+	if err != nil {
+		t.Fatalf("Failed to parse source: %v", err)
+	}
+
+	if len(types.Structs) != 1 || len(types.Structs[0].Fields) != 1 {
+		t.Fatalf("Expected Foo struct with 1 field, got %+v", types.Structs)
+	}
+
+	field := types.Structs[0].Fields[0]
+	if field.Type != "char **" {
+		t.Errorf("Expected field type 'char **', got %q", field.Type)
+	}
+
+	var buf strings.Builder
+	if err := WriteTypes(&buf, types, "raylib", Target{}); err != nil {
+		t.Fatalf("Failed to write types: %v", err)
+	}
+
+	if code := buf.String(); !strings.Contains(code, "Names *string") {
+		t.Errorf("Expected rendered field 'Names *string', got:\n%s", code)
+	}
+}
+
+// TestWriteTypes verifies Go code generation for structs, enums, and
+// typedefs
+func TestWriteTypes(t *testing.T) {
+	types := Types{
+		Structs: []Struct{
+			{Name: "Vector2", Fields: []Field{{Type: "float", Name: "x"}, {Type: "float", Name: "y"}}},
+		},
+		Enums: []Enum{
+			{Name: "TraceLogLevel", Values: []EnumValue{{Name: "LOG_ALL", Value: "0"}, {Name: "LOG_TRACE"}}},
+		},
+		Typedefs: []Typedef{
+			{Name: "RLuint", Underlying: "unsigned int"},
+		},
+	}
+
+	var buf strings.Builder
+	if err := WriteTypes(&buf, types, "raylib", Target{}); err != nil {
+		t.Fatalf("Failed to write types: %v", err)
+	}
+
+	code := buf.String()
+	expectedPatterns := []string{
+		"package raylib",
+		"type Vector2 struct {",
+		"X float32",
+		"Y float32",
+		"type TraceLogLevel int32",
+		"LOG_ALL",
+		"TraceLogLevel = 0",
+		"LOG_TRACE",
+		"TraceLogLevel = LOG_ALL + 1",
+		"type RLuint uint32",
+	}
+
+	for _, pattern := range expectedPatterns {
+		if !strings.Contains(code, pattern) {
+			t.Errorf("Generated code doesn't contain expected pattern: %s", pattern)
+		}
+	}
+}
+
+// TestWriteTypesEnumImplicitAfterExplicit verifies that an enumerator
+// following an explicit value chains off that value rather than its
+// position in the Go const block, e.g. C's `FLAG_A = 5, FLAG_B, FLAG_C`
+// (FLAG_B=6, FLAG_C=7)
+func TestWriteTypesEnumImplicitAfterExplicit(t *testing.T) {
+	types := Types{
+		Enums: []Enum{
+			{Name: "Flags", Values: []EnumValue{
+				{Name: "FLAG_A", Value: "5"},
+				{Name: "FLAG_B"},
+				{Name: "FLAG_C"},
+			}},
+		},
+	}
+
+	var buf strings.Builder
+	if err := WriteTypes(&buf, types, "raylib", Target{}); err != nil {
+		t.Fatalf("Failed to write types: %v", err)
+	}
+
+	code := buf.String()
+	expectedPatterns := []string{
+		"FLAG_A Flags = 5",
+		"FLAG_B Flags = FLAG_A + 1",
+		"FLAG_C Flags = FLAG_B + 1",
+	}
+	for _, pattern := range expectedPatterns {
+		if !strings.Contains(code, pattern) {
+			t.Errorf("Generated code doesn't contain expected pattern: %s\n--- code ---\n%s", pattern, code)
+		}
+	}
+}
+
+// TestGeneratePureGoShimTarget verifies that an explicit target produces a
+// suffixed, build-tagged file declaring the CLong/CSizeT/CWChar aliases
+func TestGeneratePureGoShimTarget(t *testing.T) {
+	functions := []Function{
+		{
+			Name: "GetFileLength",
+			Type: "long",
+			Params: []Param{
+				{Type: "const char *", Name: "fileName"},
+			},
+		},
+	}
+
+	tempDir, err := os.MkdirTemp("", "purego-test-target")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		os.RemoveAll(tempDir)
+	}()
+
+	target := Target{GOOS: "linux", GOARCH: "amd64"}
+	if err := GeneratePureGoShim(functions, tempDir, "test.h", "raylib", target, nil); err != nil {
+		t.Fatalf("Failed to generate code: %v", err)
+	}
+
+	outputFile := filepath.Join(tempDir, "test_linux_amd64.go")
+	codeBytes, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Expected output file %s: %v", outputFile, err)
+	}
+	code := string(codeBytes)
+
+	expectedPatterns := []string{
+		"//go:build linux && amd64",
+		"package raylib",
+		"type CLong = int64",
+		"var GetFileLength func(fileName string) CLong",
+	}
+	for _, pattern := range expectedPatterns {
+		if !strings.Contains(code, pattern) {
+			t.Errorf("Generated code doesn't contain expected pattern: %s\n--- code ---\n%s", pattern, code)
+		}
+	}
+}
+
+// TestWritePureGoShimWrapperCString verifies that a wrapped function
+// returning char* gets a raw unsafe.Pointer binding plus an exported
+// wrapper that copies the string and frees the C allocation
+func TestWritePureGoShimWrapperCString(t *testing.T) {
+	functions := []Function{
+		{
+			Name: "LoadFileText",
+			Type: "char *",
+			Params: []Param{
+				{Type: "const char *", Name: "fileName"},
+			},
+		},
+	}
+	annotations := Annotations{"LoadFileText": {Wrapper: true}}
+
+	var buf strings.Builder
+	if err := WritePureGoShim(&buf, functions, "test.h", "raylib", Target{}, annotations); err != nil {
+		t.Fatalf("Failed to write shim: %v", err)
+	}
+
+	code := buf.String()
+	expectedPatterns := []string{
+		"var _LoadFileText func(fileName string) unsafe.Pointer",
+		"var _free func(unsafe.Pointer)",
+		"var _strlen func(unsafe.Pointer) uintptr",
+		"func cStringToGo(ptr unsafe.Pointer) string {",
+		"func LoadFileText(fileName string) string {",
+		"ret := _LoadFileText(fileName)",
+		"return cStringToGo(ret)",
+		"purego.RegisterLibFunc(&_LoadFileText, handle, \"LoadFileText\")",
+		"purego.RegisterLibFunc(&_free, handle, \"free\")",
+		"purego.RegisterLibFunc(&_strlen, handle, \"strlen\")",
+	}
+	for _, pattern := range expectedPatterns {
+		if !strings.Contains(code, pattern) {
+			t.Errorf("Generated code doesn't contain expected pattern: %s\n--- code ---\n%s", pattern, code)
+		}
+	}
+}
+
+// TestWritePureGoShimWrapperOutParams verifies that pointer parameters
+// matching the out-parameter convention become extra wrapper return values
+func TestWritePureGoShimWrapperOutParams(t *testing.T) {
+	functions := []Function{
+		{
+			Name: "LoadFileData",
+			Type: "unsigned char *",
+			Params: []Param{
+				{Type: "const char *", Name: "fileName"},
+				{Type: "int *", Name: "dataSize"},
+			},
+		},
+	}
+	annotations := Annotations{"LoadFileData": {Wrapper: true}}
+
+	var buf strings.Builder
+	if err := WritePureGoShim(&buf, functions, "test.h", "raylib", Target{}, annotations); err != nil {
+		t.Fatalf("Failed to write shim: %v", err)
+	}
+
+	code := buf.String()
+	expectedPatterns := []string{
+		"var _LoadFileData func(fileName string, dataSize *int32) *uint8",
+		"func LoadFileData(fileName string) (*uint8, int32) {",
+		"var dataSize int32",
+		"ret := _LoadFileData(fileName, &dataSize)",
+		"return ret, dataSize",
+	}
+	for _, pattern := range expectedPatterns {
+		if !strings.Contains(code, pattern) {
+			t.Errorf("Generated code doesn't contain expected pattern: %s\n--- code ---\n%s", pattern, code)
+		}
+	}
+}
+
+// TestWritePureGoShimWrapperNotOutParam verifies that a pointer param whose
+// name merely starts with "out" followed by a lowercase letter (as opposed to
+// the camelCase `outFoo` convention) is treated as a regular argument, not an
+// out-parameter
+func TestWritePureGoShimWrapperNotOutParam(t *testing.T) {
+	functions := []Function{
+		{
+			Name: "DrawTextOutlined",
+			Type: "void",
+			Params: []Param{
+				{Type: "const char *", Name: "text"},
+				{Type: "int *", Name: "outline"},
+			},
+		},
+	}
+	annotations := Annotations{"DrawTextOutlined": {Wrapper: true}}
+
+	var buf strings.Builder
+	if err := WritePureGoShim(&buf, functions, "test.h", "raylib", Target{}, annotations); err != nil {
+		t.Fatalf("Failed to write shim: %v", err)
+	}
+
+	code := buf.String()
+	expectedPatterns := []string{
+		"var _DrawTextOutlined func(text string, outline *int32)",
+		"func DrawTextOutlined(text string, outline *int32) {",
+		"_DrawTextOutlined(text, outline)",
+	}
+	for _, pattern := range expectedPatterns {
+		if !strings.Contains(code, pattern) {
+			t.Errorf("Generated code doesn't contain expected pattern: %s\n--- code ---\n%s", pattern, code)
+		}
+	}
+	if strings.Contains(code, "(text string) int32") {
+		t.Errorf("outline was incorrectly treated as an out-parameter:\n%s", code)
+	}
+}
+
+// TestWritePureGoShimWrapperOutParamDoublePointer verifies that a `char **`
+// out-parameter is treated as pointing at a `char *` (i.e. a string), not
+// collapsed all the way down to a single byte
+func TestWritePureGoShimWrapperOutParamDoublePointer(t *testing.T) {
+	functions := []Function{
+		{
+			Name: "GetDroppedFiles",
+			Type: "void",
+			Params: []Param{
+				{Type: "char **", Name: "outFiles"},
+			},
+		},
+	}
+	annotations := Annotations{"GetDroppedFiles": {Wrapper: true}}
+
+	var buf strings.Builder
+	if err := WritePureGoShim(&buf, functions, "test.h", "raylib", Target{}, annotations); err != nil {
+		t.Fatalf("Failed to write shim: %v", err)
+	}
+
+	code := buf.String()
+	expectedPatterns := []string{
+		"var _GetDroppedFiles func(outFiles *string)",
+		"func GetDroppedFiles() string {",
+		"var outFiles string",
+		"_GetDroppedFiles(&outFiles)",
+		"return outFiles",
+	}
+	for _, pattern := range expectedPatterns {
+		if !strings.Contains(code, pattern) {
+			t.Errorf("Generated code doesn't contain expected pattern: %s\n--- code ---\n%s", pattern, code)
+		}
+	}
+}
+
+// TestWritePureGoShimWrapperBuffer verifies that an `unsigned char *`
+// parameter becomes a []byte in a wrapped function, converted via
+// unsafe.SliceData for the raw call
+func TestWritePureGoShimWrapperBuffer(t *testing.T) {
+	functions := []Function{
+		{
+			Name: "UpdateTexture",
+			Type: "void",
+			Params: []Param{
+				{Type: "Texture", Name: "texture"},
+				{Type: "unsigned char *", Name: "pixels"},
+			},
+		},
+	}
+	annotations := Annotations{"UpdateTexture": {Wrapper: true}}
+
+	var buf strings.Builder
+	if err := WritePureGoShim(&buf, functions, "test.h", "raylib", Target{}, annotations); err != nil {
+		t.Fatalf("Failed to write shim: %v", err)
+	}
+
+	code := buf.String()
+	expectedPatterns := []string{
+		"var _UpdateTexture func(texture Texture, pixels *uint8)",
+		"func UpdateTexture(texture Texture, pixels []byte) {",
+		"_UpdateTexture(texture, (*uint8)(unsafe.Pointer(unsafe.SliceData(pixels))))",
+	}
+	for _, pattern := range expectedPatterns {
+		if !strings.Contains(code, pattern) {
+			t.Errorf("Generated code doesn't contain expected pattern: %s\n--- code ---\n%s", pattern, code)
+		}
+	}
+}
+
+// TestWritePureGoShimUnannotated verifies that functions with no
+// annotation keep the plain raw var binding, unaffected by wrapper support
+func TestWritePureGoShimUnannotated(t *testing.T) {
+	functions := []Function{
+		{Name: "GetRandomValue", Type: "int", Params: []Param{{Type: "int", Name: "min"}, {Type: "int", Name: "max"}}},
+	}
+
+	var buf strings.Builder
+	if err := WritePureGoShim(&buf, functions, "test.h", "raylib", Target{}, Annotations{"GetRandomValue": {}}); err != nil {
+		t.Fatalf("Failed to write shim: %v", err)
+	}
+
+	code := buf.String()
+	if !strings.Contains(code, "var GetRandomValue func(min int32, max int32) int32") {
+		t.Errorf("Expected unannotated function to keep its plain var binding, got:\n%s", code)
+	}
+	if strings.Contains(code, "_GetRandomValue") {
+		t.Errorf("Unannotated function shouldn't generate a raw/wrapper pair, got:\n%s", code)
+	}
+}
+
+// TestLoadAnnotations verifies annotations are parsed from a JSON file, and
+// that a missing file is treated as "no annotations" rather than an error
+func TestLoadAnnotations(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "annotations-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp directory: %v", err)
+	}
+	defer func() {
+		os.RemoveAll(tempDir)
+	}()
+
+	path := filepath.Join(tempDir, "raylib.annotations.json")
+	contents := `{"LoadFileData": {"wrapper": true, "outParams": ["dataSize"]}}`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("Failed to write annotations file: %v", err)
+	}
+
+	annotations, err := LoadAnnotations(path)
+	if err != nil {
+		t.Fatalf("Failed to load annotations: %v", err)
+	}
+
+	ann, ok := annotations["LoadFileData"]
+	if !ok || !ann.Wrapper || len(ann.OutParams) != 1 || ann.OutParams[0] != "dataSize" {
+		t.Errorf("Expected LoadFileData annotation with wrapper=true and outParams=[dataSize], got %+v", ann)
+	}
+
+	missing, err := LoadAnnotations(filepath.Join(tempDir, "missing.annotations.json"))
+	if err != nil {
+		t.Fatalf("Expected a missing annotations file to not be an error, got: %v", err)
+	}
+	if len(missing) != 0 {
+		t.Errorf("Expected no annotations for a missing file, got %+v", missing)
+	}
+}
+
+// TestGetFunctionsFromSourceFuncPtrParam verifies that an inline C
+// function-pointer parameter is parsed into a FuncPtr param
+func TestGetFunctionsFromSourceFuncPtrParam(t *testing.T) {
+	source := []byte(`
+RLAPI void SetTraceLogCallback(void (*callback)(int logLevel, const char *text)); // Set custom trace log callback
+	`)
+
+	functions, err := GetFunctionsFromSource(source)
+	if err != nil {
+		t.Fatalf("Failed to parse source: %v", err)
+	}
+
+	if len(functions) != 1 || len(functions[0].Params) != 1 {
+		t.Fatalf("Expected 1 function with 1 parameter, got %+v", functions)
+	}
+
+	param := functions[0].Params[0]
+	if param.Name != "callback" {
+		t.Errorf("Expected param name 'callback', got %q", param.Name)
+	}
+	if param.FuncPtr == nil {
+		t.Fatalf("Expected callback parameter to be parsed as a FuncPtr")
+	}
+	if param.FuncPtr.Return != "void" {
+		t.Errorf("Expected FuncPtr return type 'void', got %q", param.FuncPtr.Return)
+	}
+	if len(param.FuncPtr.Params) != 2 {
+		t.Fatalf("Expected 2 FuncPtr params, got %+v", param.FuncPtr.Params)
+	}
+	if param.FuncPtr.Params[0].Type != "int" || param.FuncPtr.Params[0].Name != "logLevel" {
+		t.Errorf("Expected first FuncPtr param 'int logLevel', got %+v", param.FuncPtr.Params[0])
+	}
+	if param.FuncPtr.Params[1].Type != "const char *" || param.FuncPtr.Params[1].Name != "text" {
+		t.Errorf("Expected second FuncPtr param 'const char *text', got %+v", param.FuncPtr.Params[1])
+	}
+}
+
+// TestWritePureGoShimFuncPtrParam verifies that FuncPtr params render as Go
+// func types in generated shims
+func TestWritePureGoShimFuncPtrParam(t *testing.T) {
+	functions := []Function{
+		{
+			Name: "SetTraceLogCallback",
+			Type: "void",
+			Params: []Param{
+				{
+					Name: "callback",
+					FuncPtr: &FuncPtr{
+						Return: "void",
+						Params: []Param{
+							{Type: "int", Name: "logLevel"},
+							{Type: "const char *", Name: "text"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buf strings.Builder
+	if err := WritePureGoShim(&buf, functions, "raylib.h", "raylib", Target{}, nil); err != nil {
+		t.Fatalf("Failed to write shim: %v", err)
+	}
+
+	code := buf.String()
+	if !strings.Contains(code, "var SetTraceLogCallback func(callback func(logLevel int32, text string))") {
+		t.Errorf("Expected callback parameter rendered as a Go func type, got:\n%s", code)
+	}
+}
+
+// TestGetTypesFromSourceCallback verifies that callback typedefs are parsed
+// and rendered with a purego.NewCallback wrapper
+func TestGetTypesFromSourceCallback(t *testing.T) {
+	source := []byte(`
+typedef void (*TraceLogCallback)(int logLevel, const char *text); // Logging callback
+	`)
+
+	types, err := GetTypesFromSource(source)
+	if err != nil {
+		t.Fatalf("Failed to parse source: %v", err)
+	}
+
+	if len(types.Callbacks) != 1 {
+		t.Fatalf("Expected 1 callback, got %+v", types.Callbacks)
+	}
+
+	callback := types.Callbacks[0]
+	if callback.Name != "TraceLogCallback" {
+		t.Errorf("Expected callback name 'TraceLogCallback', got %q", callback.Name)
+	}
+	if len(callback.Params) != 2 {
+		t.Fatalf("Expected 2 callback params, got %+v", callback.Params)
+	}
+
+	var buf strings.Builder
+	if err := WriteTypes(&buf, types, "raylib", Target{}); err != nil {
+		t.Fatalf("Failed to write types: %v", err)
+	}
+
+	code := buf.String()
+	expectedPatterns := []string{
+		"\"github.com/ebitengine/purego\"",
+		"type TraceLogCallback func(logLevel int32, text string)",
+		"func NewTraceLogCallback(fn TraceLogCallback) uintptr {",
+		"return purego.NewCallback(fn)",
+	}
+	for _, pattern := range expectedPatterns {
+		if !strings.Contains(code, pattern) {
+			t.Errorf("Generated code doesn't contain expected pattern: %s", pattern)
+		}
+	}
+}